@@ -36,3 +36,11 @@ const ErrorTargetVersionNotFound = Error("Target migration version was not found
 
 // ErrorSomeMigrationsAreAbsent means that some migrations files are absent.
 const ErrorSomeMigrationsAreAbsent = Error("Some migrations are absent")
+
+// ErrorTransactionsUnsupported means that transactions were requested but the connected MongoDB
+// deployment (typically a standalone server) does not support them, so the migration was applied
+// without transactional guarantees.
+const ErrorTransactionsUnsupported = Error("Transactions are not supported by the connected MongoDB deployment")
+
+// ErrorMigrationLockHeld means that another process already holds the advisory migration lock.
+const ErrorMigrationLockHeld = Error("Migration lock is held by another process")
@@ -0,0 +1,86 @@
+package migrator
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Devoter/mongo-migrator/migration"
+)
+
+// lockDocumentID is the fixed `_id` of the single advisory lock document in `migrations_lock`.
+const lockDocumentID = "lock"
+
+// lockPollInterval is how often acquireLock retries while waiting for a held lock to be released.
+const lockPollInterval = 200 * time.Millisecond
+
+// lockReleaseTimeout bounds how long releaseLock waits on its detached context, so a lock is never
+// orphaned just because the run's own context was cancelled or hit its deadline.
+const lockReleaseTimeout = 10 * time.Second
+
+// lockDocument is the advisory lock record stored in the `migrations_lock` collection while a migrator
+// run is in progress, preventing two processes from racing on `Up`/`Down`/`Reset`/`SetVersion`.
+type lockDocument struct {
+	ID         string    `bson:"_id"`
+	AcquiredAt time.Time `bson:"acquired_at"`
+}
+
+// acquireLock inserts the advisory lock document, relying on the unique `_id` to reject a second
+// holder. If the lock is already held it retries every `lockPollInterval` until `lockTimeout` elapses,
+// then returns `ErrorMigrationLockHeld`. Locking is skipped entirely when disabled via `WithoutLock`.
+func (m *Migrator) acquireLock(ctx context.Context, db *mongo.Database) error {
+	if !m.lockEnabled {
+		return nil
+	}
+
+	coll := db.Collection("migrations_lock", migration.MajorityOpts())
+	deadline := time.Now().Add(m.lockTimeout)
+
+	for {
+		_, err := coll.InsertOne(ctx, lockDocument{ID: lockDocumentID, AcquiredAt: time.Now()})
+		if err == nil {
+			return nil
+		}
+
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+
+		if m.lockTimeout <= 0 || time.Now().After(deadline) {
+			return ErrorMigrationLockHeld
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// releaseLock removes the advisory lock document. It is a no-op when locking is disabled. It detaches
+// from ctx's cancellation (bounding itself with its own `lockReleaseTimeout` instead), so a run whose
+// context was cancelled or hit its deadline still releases the lock rather than orphaning it.
+func (m *Migrator) releaseLock(ctx context.Context, db *mongo.Database) error {
+	if !m.lockEnabled {
+		return nil
+	}
+
+	releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lockReleaseTimeout)
+	defer cancel()
+
+	_, err := db.Collection("migrations_lock", migration.MajorityOpts()).DeleteOne(releaseCtx, bson.D{{"_id", lockDocumentID}})
+	return err
+}
+
+// releaseLockLogged calls releaseLock and logs (rather than silently discarding) any error. Advisory
+// locking exists specifically so concurrent runs can't interleave, so a release that fails without a
+// trace would leave `migrations_lock` orphaned and turn every subsequent run into a confusing
+// `ErrorMigrationLockHeld` with no clue why. Intended to be deferred: `defer m.releaseLockLogged(ctx, db)`.
+func (m *Migrator) releaseLockLogged(ctx context.Context, db *mongo.Database) {
+	if err := m.releaseLock(ctx, db); err != nil {
+		m.logger.Errorf("failed to release migration lock: %v", err)
+	}
+}
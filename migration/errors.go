@@ -0,0 +1,23 @@
+package migration
+
+// Error declares constant error type.
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// ErrorUnequalCountsOfMigrations means that a migration found on disk is missing its `up` or `down` half.
+const ErrorUnequalCountsOfMigrations = Error("Unequal counts of `up` and `down` migrations")
+
+// ErrorMigrationNotFound means that no migration matching the requested version could be found.
+const ErrorMigrationNotFound = Error("Migration not found")
+
+// ErrorMissingGoMigration means that `LoadFromFS` found a `.up.go`/`.down.go` migration file on disk
+// but the `goMigrations` map passed in has no entry for its version, or that entry's `Up`/`Down` are nil.
+const ErrorMissingGoMigration = Error("Missing Go migration registration")
+
+// ErrorMismatchedMigrationHalves means that `LoadFromFS` found a migration whose `up` half is authored
+// in Go but whose `down` half is authored in JavaScript, or vice versa. A migration's two halves must
+// agree on language, since a Go-registered `ApplyFunc` can't be paired with an on-disk `.js` script.
+const ErrorMismatchedMigrationHalves = Error("Mismatched migration halves (Go and JavaScript)")
@@ -0,0 +1,218 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// upSuffix and downSuffix are the file name suffixes `LoadFromFS` recognizes for the JavaScript half of
+// a migration, e.g. `20240115093000_add_users_index.up.js`. upGoSuffix and downGoSuffix are the
+// equivalent suffixes for migrations authored as Go code rather than JavaScript; see `GoMigration`.
+const (
+	upSuffix   = ".up.js"
+	downSuffix = ".down.js"
+
+	upGoSuffix   = ".up.go"
+	downGoSuffix = ".down.go"
+)
+
+// GoMigration supplies the Up/Down `ApplyFunc` for a migration authored as compiled Go code rather than
+// JavaScript. Pass a map of these, keyed by `Migration.Version`, to `LoadFromFS` so that any
+// `.up.go`/`.down.go` pair found on disk is wired to real Go functions instead of requiring the MongoDB
+// `eval` command, which `jsApplyFunc` depends on and which is deprecated, disabled by default on many
+// deployments, unsupported on sharded clusters/mongos, and unavailable on MongoDB Atlas. The `.go` files
+// themselves are never read or executed by `LoadFromFS` — they only mark that version as "authored in
+// Go" and exist so the migration still shows up alongside its JavaScript siblings in directory listings
+// and `NewMigrationFiles`-style tooling; the real code must be registered here ahead of time, since
+// there is no way to compile and load arbitrary `.go` source at runtime without the `plugin` package's
+// platform/build restrictions.
+type GoMigration struct {
+	Up   ApplyFunc
+	Down ApplyFunc
+}
+
+// LoadFromFS scans dir within fsys for paired migration files named like
+// `20240115093000_add_users_index.up.js` / `20240115093000_add_users_index.down.js`, parses the leading
+// int64 timestamp as Version, and returns the resulting migrations sorted by version. Each `.js` half is
+// run via `db.RunCommand` with `eval` — a command that is deprecated, disabled by default on many
+// deployments, unsupported on sharded clusters/mongos, and unavailable on MongoDB Atlas. Migrations
+// targeting such deployments should instead be named `<version>_<name>.up.go` /
+// `<version>_<name>.down.go` and have their `ApplyFunc`s supplied via goMigrations, keyed by version;
+// goMigrations may be nil if every migration on disk is a `.js` pair. Returns
+// `ErrorUnequalCountsOfMigrations` when a migration is missing its `up` or `down` half,
+// `ErrorMismatchedMigrationHalves` when one half is `.go` and the other is `.js`, and
+// `ErrorMissingGoMigration` when a `.go` half has no usable entry in goMigrations.
+func LoadFromFS(fsys fs.FS, dir string, goMigrations map[int64]GoMigration) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type halves struct {
+		version  int64
+		name     string
+		upPath   string
+		upIsGo   bool
+		downPath string
+		downIsGo bool
+	}
+
+	byVersion := make(map[int64]*halves)
+	order := make([]int64, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		version, name, direction, isGo, ok := parseMigrationFileName(entry.Name())
+		if !ok {
+			continue
+		}
+
+		h, exists := byVersion[version]
+		if !exists {
+			h = &halves{version: version, name: name}
+			byVersion[version] = h
+			order = append(order, version)
+		}
+
+		switch direction {
+		case "up":
+			h.upPath = path.Join(dir, entry.Name())
+			h.upIsGo = isGo
+		case "down":
+			h.downPath = path.Join(dir, entry.Name())
+			h.downIsGo = isGo
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, version := range order {
+		h := byVersion[version]
+
+		if h.upPath == "" || h.downPath == "" {
+			return nil, ErrorUnequalCountsOfMigrations
+		}
+
+		if h.upIsGo != h.downIsGo {
+			return nil, ErrorMismatchedMigrationHalves
+		}
+
+		migr := Migration{Version: h.version, Name: h.name}
+
+		if h.upIsGo {
+			goMigr, ok := goMigrations[h.version]
+			if !ok || goMigr.Up == nil || goMigr.Down == nil {
+				return nil, ErrorMissingGoMigration
+			}
+
+			migr.Up = goMigr.Up
+			migr.Down = goMigr.Down
+		} else {
+			migr.Up = jsApplyFunc(fsys, h.upPath)
+			migr.Down = jsApplyFunc(fsys, h.downPath)
+		}
+
+		migrations = append(migrations, migr)
+	}
+
+	sort.Sort(Migrations(migrations))
+
+	return migrations, nil
+}
+
+// NewMigrationFiles scaffolds an empty paired `up`/`down` migration file set on disk under dir, named
+// `<version>_<name>.up.js` / `<version>_<name>.down.js`, and returns their paths. It is the companion
+// of `LoadFromFS`, letting migrations be authored as plain files rather than hand-registered slices.
+// It refuses to overwrite either file if one already exists at that path (e.g. a rerun or a version
+// collision), returning an error instead.
+func NewMigrationFiles(dir string, version int64, name string) (upPath string, downPath string, err error) {
+	base := fmt.Sprintf("%d_%s", version, name)
+	upPath = filepath.Join(dir, base+upSuffix)
+	downPath = filepath.Join(dir, base+downSuffix)
+
+	for _, p := range []string{upPath, downPath} {
+		var f *os.File
+
+		f, err = os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return
+		}
+
+		_, err = f.Write([]byte("// migration script\n"))
+		closeErr := f.Close()
+
+		if err != nil {
+			return
+		}
+		if closeErr != nil {
+			err = closeErr
+			return
+		}
+	}
+
+	return
+}
+
+// parseMigrationFileName splits a migration file name of the form `<version>_<name>.<up|down>.<js|go>`
+// into its version, name and direction, reporting via isGo whether it is the Go-authored variant. It
+// reports `ok == false` for names that do not match this pattern.
+func parseMigrationFileName(name string) (version int64, migrationName string, direction string, isGo bool, ok bool) {
+	suffixes := []struct {
+		suffix string
+		dir    string
+		isGo   bool
+	}{
+		{upSuffix, "up", false},
+		{downSuffix, "down", false},
+		{upGoSuffix, "up", true},
+		{downGoSuffix, "down", true},
+	}
+
+	for _, s := range suffixes {
+		if !strings.HasSuffix(name, s.suffix) {
+			continue
+		}
+
+		base := strings.TrimSuffix(name, s.suffix)
+		idx := strings.Index(base, "_")
+
+		if idx <= 0 {
+			return 0, "", "", false, false
+		}
+
+		v, err := strconv.ParseInt(base[:idx], 10, 64)
+		if err != nil {
+			return 0, "", "", false, false
+		}
+
+		return v, base[idx+1:], s.dir, s.isGo, true
+	}
+
+	return 0, "", "", false, false
+}
+
+// jsApplyFunc returns an ApplyFunc that runs a JavaScript migration file's contents against db via
+// `db.RunCommand` with `eval`. See the `eval` caveats documented on `LoadFromFS`.
+func jsApplyFunc(fsys fs.FS, filePath string) ApplyFunc {
+	return func(ctx context.Context, db *mongo.Database) error {
+		script, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return err
+		}
+
+		return db.RunCommand(ctx, bson.D{{"eval", string(script)}}).Err()
+	}
+}
@@ -0,0 +1,228 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestParseMigrationFileName(t *testing.T) {
+	cases := []struct {
+		name          string
+		fileName      string
+		wantVersion   int64
+		wantName      string
+		wantDirection string
+		wantIsGo      bool
+		wantOk        bool
+	}{
+		{
+			name:          "up js",
+			fileName:      "20240115093000_add_users_index.up.js",
+			wantVersion:   20240115093000,
+			wantName:      "add_users_index",
+			wantDirection: "up",
+			wantIsGo:      false,
+			wantOk:        true,
+		},
+		{
+			name:          "down js",
+			fileName:      "20240115093000_add_users_index.down.js",
+			wantVersion:   20240115093000,
+			wantName:      "add_users_index",
+			wantDirection: "down",
+			wantIsGo:      false,
+			wantOk:        true,
+		},
+		{
+			name:          "up go",
+			fileName:      "20240115093000_add_users_index.up.go",
+			wantVersion:   20240115093000,
+			wantName:      "add_users_index",
+			wantDirection: "up",
+			wantIsGo:      true,
+			wantOk:        true,
+		},
+		{
+			name:          "down go",
+			fileName:      "20240115093000_add_users_index.down.go",
+			wantVersion:   20240115093000,
+			wantName:      "add_users_index",
+			wantDirection: "down",
+			wantIsGo:      true,
+			wantOk:        true,
+		},
+		{
+			name:          "name contains underscores",
+			fileName:      "1_a_b_c.up.js",
+			wantVersion:   1,
+			wantName:      "a_b_c",
+			wantDirection: "up",
+			wantIsGo:      false,
+			wantOk:        true,
+		},
+		{
+			name:     "no underscore separator",
+			fileName: "20240115093000.up.js",
+			wantOk:   false,
+		},
+		{
+			name:     "non-numeric version",
+			fileName: "abc_add_users_index.up.js",
+			wantOk:   false,
+		},
+		{
+			name:     "unrecognized extension",
+			fileName: "20240115093000_add_users_index.up.ts",
+			wantOk:   false,
+		},
+		{
+			name:     "not a migration file at all",
+			fileName: "README.md",
+			wantOk:   false,
+		},
+		{
+			name:     "empty name before underscore",
+			fileName: "_add_users_index.up.js",
+			wantOk:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			version, name, direction, isGo, ok := parseMigrationFileName(tc.fileName)
+
+			if ok != tc.wantOk {
+				t.Fatalf("ok = %v, want %v", ok, tc.wantOk)
+			}
+
+			if !tc.wantOk {
+				return
+			}
+
+			if version != tc.wantVersion {
+				t.Errorf("version = %d, want %d", version, tc.wantVersion)
+			}
+			if name != tc.wantName {
+				t.Errorf("name = %q, want %q", name, tc.wantName)
+			}
+			if direction != tc.wantDirection {
+				t.Errorf("direction = %q, want %q", direction, tc.wantDirection)
+			}
+			if isGo != tc.wantIsGo {
+				t.Errorf("isGo = %v, want %v", isGo, tc.wantIsGo)
+			}
+		})
+	}
+}
+
+func TestLoadFromFS_JSPair(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_init.up.js":   {Data: []byte("db.foo.insert({})")},
+		"migrations/1_init.down.js": {Data: []byte("db.foo.remove({})")},
+	}
+
+	migrations, err := LoadFromFS(fsys, "migrations", nil)
+	if err != nil {
+		t.Fatalf("LoadFromFS returned error: %v", err)
+	}
+
+	if len(migrations) != 1 {
+		t.Fatalf("len(migrations) = %d, want 1", len(migrations))
+	}
+
+	if migrations[0].Version != 1 || migrations[0].Name != "init" {
+		t.Errorf("got migration %+v, want version=1 name=init", migrations[0])
+	}
+
+	if migrations[0].Up == nil || migrations[0].Down == nil {
+		t.Error("expected non-nil Up/Down ApplyFuncs for a JS pair")
+	}
+}
+
+func TestLoadFromFS_UnequalHalves(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_init.up.js": {Data: []byte("db.foo.insert({})")},
+	}
+
+	_, err := LoadFromFS(fsys, "migrations", nil)
+	if err != ErrorUnequalCountsOfMigrations {
+		t.Fatalf("err = %v, want ErrorUnequalCountsOfMigrations", err)
+	}
+}
+
+func TestLoadFromFS_MismatchedHalves(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_init.up.go":   {Data: []byte("// go migration")},
+		"migrations/1_init.down.js": {Data: []byte("db.foo.remove({})")},
+	}
+
+	goMigrations := map[int64]GoMigration{
+		1: {
+			Up:   func(ctx context.Context, db *mongo.Database) error { return nil },
+			Down: func(ctx context.Context, db *mongo.Database) error { return nil },
+		},
+	}
+
+	_, err := LoadFromFS(fsys, "migrations", goMigrations)
+	if err != ErrorMismatchedMigrationHalves {
+		t.Fatalf("err = %v, want ErrorMismatchedMigrationHalves", err)
+	}
+}
+
+func TestLoadFromFS_MissingGoMigration(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_init.up.go":   {Data: []byte("// go migration")},
+		"migrations/1_init.down.go": {Data: []byte("// go migration")},
+	}
+
+	_, err := LoadFromFS(fsys, "migrations", nil)
+	if err != ErrorMissingGoMigration {
+		t.Fatalf("err = %v, want ErrorMissingGoMigration", err)
+	}
+}
+
+func TestLoadFromFS_GoMigrationWithNilFunc(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_init.up.go":   {Data: []byte("// go migration")},
+		"migrations/1_init.down.go": {Data: []byte("// go migration")},
+	}
+
+	goMigrations := map[int64]GoMigration{
+		1: {Up: func(ctx context.Context, db *mongo.Database) error { return nil }},
+	}
+
+	_, err := LoadFromFS(fsys, "migrations", goMigrations)
+	if err != ErrorMissingGoMigration {
+		t.Fatalf("err = %v, want ErrorMissingGoMigration for a GoMigration missing Down", err)
+	}
+}
+
+func TestLoadFromFS_GoPair(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_init.up.go":   {Data: []byte("// go migration")},
+		"migrations/1_init.down.go": {Data: []byte("// go migration")},
+	}
+
+	goMigrations := map[int64]GoMigration{
+		1: {
+			Up:   func(ctx context.Context, db *mongo.Database) error { return nil },
+			Down: func(ctx context.Context, db *mongo.Database) error { return nil },
+		},
+	}
+
+	migrations, err := LoadFromFS(fsys, "migrations", goMigrations)
+	if err != nil {
+		t.Fatalf("LoadFromFS returned error: %v", err)
+	}
+
+	if len(migrations) != 1 {
+		t.Fatalf("len(migrations) = %d, want 1", len(migrations))
+	}
+
+	if migrations[0].Up == nil || migrations[0].Down == nil {
+		t.Error("expected the registered GoMigration's Up/Down to be wired in")
+	}
+}
@@ -0,0 +1,64 @@
+package migration
+
+import (
+	"fmt"
+	"os"
+)
+
+// Logger declares the logging interface the migrator uses to report progress. Embedders that want to
+// route migration logs into their own logging stack can implement this interface and pass it to
+// `migrator.WithLogger`.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Debugf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// stdLogger is the standard `Logger` implementation, writing to `os.Stdout`, used by
+// `migrator.WithVerbose`. Debug messages are only emitted when verbose is true.
+type stdLogger struct {
+	verbose bool
+}
+
+// NewStdLogger returns a `Logger` that writes to `os.Stdout`. When verbose is false, `Debugf` calls are
+// discarded.
+func NewStdLogger(verbose bool) Logger {
+	return &stdLogger{verbose: verbose}
+}
+
+// Infof implements `Logger`.
+func (l *stdLogger) Infof(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stdout, format+"\n", args...)
+}
+
+// Debugf implements `Logger`.
+func (l *stdLogger) Debugf(format string, args ...interface{}) {
+	if !l.verbose {
+		return
+	}
+
+	fmt.Fprintf(os.Stdout, format+"\n", args...)
+}
+
+// Errorf implements `Logger`.
+func (l *stdLogger) Errorf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stdout, format+"\n", args...)
+}
+
+// noopLogger discards every message. It is the default logger, used until `WithLogger`/`WithVerbose`
+// configures one.
+type noopLogger struct{}
+
+// NewNoopLogger returns a `Logger` that discards every message.
+func NewNoopLogger() Logger {
+	return noopLogger{}
+}
+
+// Infof implements `Logger`.
+func (noopLogger) Infof(format string, args ...interface{}) {}
+
+// Debugf implements `Logger`.
+func (noopLogger) Debugf(format string, args ...interface{}) {}
+
+// Errorf implements `Logger`.
+func (noopLogger) Errorf(format string, args ...interface{}) {}
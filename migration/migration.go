@@ -1,17 +1,32 @@
 package migration
 
-import "go.mongodb.org/mongo-driver/mongo"
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
 
 // ApplyFunc declares func type for migration functions
-type ApplyFunc func(db *mongo.Database) error
+type ApplyFunc func(ctx context.Context, db *mongo.Database) error
+
+// DummyUpDown is a no-op ApplyFunc. It is used as the `Up`/`Down` of the implicit zero migration that
+// `migrator.NewMigrator` registers alongside the caller's own migrations.
+func DummyUpDown(ctx context.Context, db *mongo.Database) error {
+	return nil
+}
 
 // Migration declares a migration data structure.
 type Migration struct {
-	Version int64     `json:"version" bson:"version"`
-	Name    string    `json:"name" bson:"name"`
-	Up      ApplyFunc `json:"-" bson:"-"`
-	Down    ApplyFunc `json:"-" bson:"-"`
-	Stored  bool      `json:"-" bson:"-"`
+	Version   int64     `json:"version" bson:"version"`
+	Name      string    `json:"name" bson:"name"`
+	AppliedAt time.Time `json:"appliedAt" bson:"applied_at"`
+	Up        ApplyFunc `json:"-" bson:"-"`
+	Down      ApplyFunc `json:"-" bson:"-"`
+	Stored    bool      `json:"-" bson:"-"`
+	// Transactions overrides the migrator's default transaction behaviour for this migration.
+	// A `nil` value inherits whatever `WithTransactions` was configured on the `Migrator`.
+	Transactions *bool `json:"-" bson:"-"`
 }
 
 // Less returns `true` if an argument is more than current.
@@ -43,3 +58,39 @@ func (ms Migrations) Less(i int, j int) bool {
 func CompareMigrations(left *Migration, right *Migration) bool {
 	return left.Version < right.Version
 }
+
+// Current returns the migration whose version equals `current`. `ms` must already be sorted by
+// version, as it is when obtained from `migrator.Migrator.Migrations`.
+func (ms Migrations) Current(current int64) (*Migration, error) {
+	for i := range ms {
+		if ms[i].Version == current {
+			return &ms[i], nil
+		}
+	}
+
+	return nil, ErrorMigrationNotFound
+}
+
+// Next returns the migration immediately after `current` in version order. `ms` must already be
+// sorted by version.
+func (ms Migrations) Next(current int64) (*Migration, error) {
+	for i := range ms {
+		if ms[i].Version > current {
+			return &ms[i], nil
+		}
+	}
+
+	return nil, ErrorMigrationNotFound
+}
+
+// Previous returns the migration immediately before `current` in version order. `ms` must already be
+// sorted by version.
+func (ms Migrations) Previous(current int64) (*Migration, error) {
+	for i := len(ms) - 1; i >= 0; i-- {
+		if ms[i].Version < current {
+			return &ms[i], nil
+		}
+	}
+
+	return nil, ErrorMigrationNotFound
+}
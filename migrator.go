@@ -2,8 +2,12 @@ package migrator
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -14,23 +18,90 @@ import (
 
 // Migrator declares MongoDB migrations manager.
 type Migrator struct {
-	client     *mongo.Client
-	migrations []migration.Migration
+	client          *mongo.Client
+	migrations      []migration.Migration
+	useTransactions bool
+	logger          migration.Logger
+	lockEnabled     bool
+	lockTimeout     time.Duration
+}
+
+// Option configures a `Migrator` created via `NewMigrator`.
+type Option func(*Migrator)
+
+// WithTransactions sets the default for whether each Up/Down step is wrapped in a MongoDB session
+// transaction, so that a migration and its corresponding record in the `migrations` collection either
+// both commit or both roll back. Individual migrations may override this default via
+// `migration.Migration.Transactions`. Transactions require a replica set or sharded cluster; on a
+// standalone server the migrator falls back to non-transactional execution; the step still succeeds,
+// `ErrorTransactionsUnsupported` is not returned to the caller, and the fallback is only logged at
+// debug level.
+func WithTransactions(enabled bool) Option {
+	return func(m *Migrator) {
+		m.useTransactions = enabled
+	}
+}
+
+// WithLogger sets the `migration.Logger` used to report migration progress. The default is a no-op
+// logger, so embedding applications see no output unless they opt in.
+func WithLogger(logger migration.Logger) Option {
+	return func(m *Migrator) {
+		m.logger = logger
+	}
+}
+
+// WithVerbose is a shorthand for `WithLogger(migration.NewStdLogger(true))`, logging each migration's
+// version, name, direction and elapsed duration to `os.Stdout`.
+func WithVerbose() Option {
+	return func(m *Migrator) {
+		m.logger = migration.NewStdLogger(true)
+	}
+}
+
+// WithLockTimeout sets how long `Up`/`Down`/`Reset`/`SetVersion` wait for the advisory migration lock
+// to be released by another process before giving up with `ErrorMigrationLockHeld`. The default is
+// zero, meaning a held lock fails immediately.
+func WithLockTimeout(d time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockTimeout = d
+	}
+}
+
+// WithoutLock disables the advisory migration lock entirely. Use this only when the caller already
+// guarantees that `Up`/`Down`/`Reset`/`SetVersion` never run concurrently against the same database.
+func WithoutLock() Option {
+	return func(m *Migrator) {
+		m.lockEnabled = false
+	}
 }
 
 // NewMigrator returns a new instance of `Migrator`.
-func NewMigrator(client *mongo.Client, migrations []migration.Migration) *Migrator {
+func NewMigrator(client *mongo.Client, migrations []migration.Migration, opts ...Option) *Migrator {
 	all := append(migrations, migration.Migration{Name: "-", Up: migration.DummyUpDown, Down: migration.DummyUpDown})
 	sort.Sort(migration.Migrations(all))
 
-	return &Migrator{
-		client:     client,
-		migrations: all,
+	m := &Migrator{
+		client:      client,
+		migrations:  all,
+		logger:      migration.NewNoopLogger(),
+		lockEnabled: true,
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
 }
 
 // Run interprets commands.
 func (m *Migrator) Run(db string, args ...string) (oldVersion int64, newVersion int64, err error) {
+	return m.RunContext(context.Background(), db, args...)
+}
+
+// RunContext is the context-aware variant of `Run`, propagating the provided context to every MongoDB
+// call made while interpreting the command.
+func (m *Migrator) RunContext(ctx context.Context, db string, args ...string) (oldVersion int64, newVersion int64, err error) {
 	if len(args) == 0 {
 		err = ErrorCommandRequired
 		return
@@ -40,7 +111,7 @@ func (m *Migrator) Run(db string, args ...string) (oldVersion int64, newVersion
 
 	switch args[0] {
 	case "init":
-		return m.Init(base)
+		return m.InitContext(ctx, base)
 	case "up":
 		var target int64
 
@@ -49,13 +120,32 @@ func (m *Migrator) Run(db string, args ...string) (oldVersion int64, newVersion
 			return
 		}
 
-		return m.Up(base, target)
+		return m.UpContext(ctx, base, target)
 	case "down":
-		return m.Down(base)
+		return m.DownContext(ctx, base)
 	case "reset":
-		return m.Reset(base)
+		return m.ResetContext(ctx, base)
 	case "version":
-		return m.Version(base)
+		return m.VersionContext(ctx, base)
+	case "status":
+		var statuses []MigrationStatus
+
+		statuses, err = m.StatusContext(ctx, base)
+		if err != nil {
+			return
+		}
+
+		for _, status := range statuses {
+			if status.Applied && status.Version > newVersion {
+				newVersion = status.Version
+			}
+
+			fmt.Fprintf(os.Stdout, "%d\t%s\tapplied=%t\tabsent=%t\tappliedAt=%s\n",
+				status.Version, status.Name, status.Applied, status.Absent, status.AppliedAt)
+		}
+
+		oldVersion = newVersion
+		return
 	case "set_version":
 		var target int64
 
@@ -64,7 +154,20 @@ func (m *Migrator) Run(db string, args ...string) (oldVersion int64, newVersion
 			return
 		}
 
-		return m.SetVersion(base, target)
+		return m.SetVersionContext(ctx, base, target)
+	case "down-to":
+		var target int64
+
+		target, err = m.parseVersion(true, args[1:]...)
+		if err != nil {
+			return
+		}
+
+		return m.DownToContext(ctx, base, target)
+	case "up-by-one":
+		return m.UpByOneContext(ctx, base)
+	case "redo":
+		return m.RedoContext(ctx, base)
 	default:
 		err = ErrorUnexpectedCommand
 		return
@@ -73,39 +176,69 @@ func (m *Migrator) Run(db string, args ...string) (oldVersion int64, newVersion
 
 // Init creates `migrations` collection if it does not exist and records the initial zero-migration.
 func (m *Migrator) Init(db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
-	migr := &migration.Migration{Name: "-"}
+	return m.InitContext(context.Background(), db)
+}
+
+// InitContext is the context-aware variant of `Init`.
+func (m *Migrator) InitContext(ctx context.Context, db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
+	start := time.Now()
+	migr := &migration.Migration{Name: "-", AppliedAt: time.Now()}
 	var mig migration.Migration
-	result := db.Collection("migrations").FindOne(context.TODO(), bson.D{{"version", 0}})
+	result := db.Collection("migrations").FindOne(ctx, bson.D{{"version", 0}})
 	if err = result.Err(); err != nil {
 		if err != mongo.ErrNoDocuments {
+			m.logger.Errorf("init failed: %v", err)
 			return
 		}
 	} else if result.Decode(&mig) == nil {
 		err = ErrorMigrationsCollectionAlreadyExists
+		m.logger.Errorf("init failed: %v", err)
+		return
+	}
+
+	if _, err = db.Collection("migrations", migration.MajorityOpts()).InsertOne(ctx, migr); err != nil {
+		m.logger.Errorf("init failed: %v", err)
 		return
 	}
 
-	_, err = db.Collection("migrations", migration.MajorityOpts()).InsertOne(context.TODO(), migr)
+	m.logger.Infof("initialized migrations collection elapsed=%s", time.Since(start))
 	return
 }
 
 // Up upgrades database revision to the target or next version.
 func (m *Migrator) Up(db *mongo.Database, target int64) (oldVersion int64, newVersion int64, err error) {
+	return m.UpContext(context.Background(), db, target)
+}
+
+// UpContext is the context-aware variant of `Up`.
+func (m *Migrator) UpContext(ctx context.Context, db *mongo.Database, target int64) (oldVersion int64, newVersion int64, err error) {
+	if err = m.acquireLock(ctx, db); err != nil {
+		return
+	}
+	defer m.releaseLockLogged(ctx, db)
+
+	return m.up(ctx, db, target)
+}
+
+// up runs the `Up` logic without acquiring the advisory lock itself. Callers must already hold it;
+// use this instead of `UpContext` when composing multiple locked steps under a single lock acquisition,
+// e.g. `RedoContext`.
+func (m *Migrator) up(ctx context.Context, db *mongo.Database, target int64) (oldVersion int64, newVersion int64, err error) {
 	opts := options.Find()
 	opts.SetSort(bson.D{{"version", 1}})
 
-	cursor, err := db.Collection("migrations").Find(context.TODO(), bson.D{}, opts)
+	cursor, err := db.Collection("migrations").Find(ctx, bson.D{}, opts)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			err = ErrorMigrationsAreNotInitialized
 		}
 		return
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
 	history := []migration.Migration{}
 
-	for cursor.Next(context.TODO()) {
+	for cursor.Next(ctx) {
 		var mig migration.Migration
 
 		if err = cursor.Decode(&mig); err != nil {
@@ -125,35 +258,75 @@ func (m *Migrator) Up(db *mongo.Database, target int64) (oldVersion int64, newVe
 
 	coll := db.Collection("migrations", migration.MajorityOpts())
 	merged := m.mergeMigrations(history, m.migrations, target)
+	overallStart := time.Now()
+	applied := 0
 
 	for _, migr := range merged {
 		if !migr.Stored {
 			newVersion = migr.Version
+			migr := migr
+			stepStart := time.Now()
+			fellBackFromTransaction := false
 
-			if err = migr.Up(db); err != nil {
+			err = m.withTransaction(ctx, m.usesTransaction(&migr), func(txCtx context.Context) error {
+				if e := migr.Up(txCtx, db); e != nil {
+					return e
+				}
+
+				migr.Stored = true
+				migr.AppliedAt = time.Now()
+				_, e := coll.InsertOne(txCtx, &migr)
+				return e
+			})
+			if err == ErrorTransactionsUnsupported {
+				fellBackFromTransaction = true
+				err = nil
+			} else if err != nil {
+				m.logger.Errorf("migration version=%d name=%q direction=up failed: %v", migr.Version, migr.Name, err)
 				return
 			}
 
-			migr.Stored = true
-
-			if _, err = coll.InsertOne(context.TODO(), &migr); err != nil {
-				return
+			if fellBackFromTransaction {
+				m.logger.Debugf("migration version=%d ran without transaction support", migr.Version)
 			}
+
+			applied++
+			m.logger.Debugf("applied migration version=%d name=%q direction=up elapsed=%s",
+				migr.Version, migr.Name, time.Since(stepStart))
 		}
 	}
 
+	m.logger.Infof("up completed old=%d new=%d applied=%d elapsed=%s", oldVersion, newVersion, applied, time.Since(overallStart))
+
 	return
 }
 
 // Down downgrades database revision to the previous version.
 func (m *Migrator) Down(db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
+	return m.DownContext(context.Background(), db)
+}
+
+// DownContext is the context-aware variant of `Down`.
+func (m *Migrator) DownContext(ctx context.Context, db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
+	if err = m.acquireLock(ctx, db); err != nil {
+		return
+	}
+	defer m.releaseLockLogged(ctx, db)
+
+	return m.down(ctx, db)
+}
+
+// down runs the `Down` logic without acquiring the advisory lock itself. Callers must already hold it;
+// use this instead of `DownContext` when composing multiple locked steps under a single lock
+// acquisition, e.g. `RedoContext`.
+func (m *Migrator) down(ctx context.Context, db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
 	opts := options.FindOne()
 
 	opts.SetSort(bson.D{{"version", -1}})
 
 	var old migration.Migration
 
-	result := db.Collection("migrations").FindOne(context.TODO(), bson.D{}, opts)
+	result := db.Collection("migrations").FindOne(ctx, bson.D{}, opts)
 	if err = result.Err(); err != nil {
 		if err == mongo.ErrNoDocuments {
 			err = ErrorMigrationsAreNotInitialized
@@ -166,6 +339,7 @@ func (m *Migrator) Down(db *mongo.Database) (oldVersion int64, newVersion int64,
 	oldVersion = old.Version
 	newVersion = old.Version
 	coll := db.Collection("migrations", migration.MajorityOpts())
+	overallStart := time.Now()
 
 	for i := len(m.migrations) - 1; i >= 0; i-- {
 		mig := m.migrations[i]
@@ -173,15 +347,35 @@ func (m *Migrator) Down(db *mongo.Database) (oldVersion int64, newVersion int64,
 		if mig.Version == old.Version {
 			if i > 0 {
 				newVersion = m.migrations[i-1].Version
-
-				if err = mig.Down(db); err != nil {
+				stepStart := time.Now()
+				fellBackFromTransaction := false
+
+				err = m.withTransaction(ctx, m.usesTransaction(&mig), func(txCtx context.Context) error {
+					if e := mig.Down(txCtx, db); e != nil {
+						return e
+					}
+
+					_, e := coll.DeleteOne(txCtx, bson.D{{"version", mig.Version}})
+					return e
+				})
+				if err == ErrorTransactionsUnsupported {
+					fellBackFromTransaction = true
+					err = nil
+				} else if err != nil {
+					m.logger.Errorf("migration version=%d name=%q direction=down failed: %v", mig.Version, mig.Name, err)
 					return
 				}
 
-				_, err = coll.DeleteOne(context.TODO(), bson.D{{"version", mig.Version}})
+				if fellBackFromTransaction {
+					m.logger.Debugf("migration version=%d ran without transaction support", mig.Version)
+				}
 
+				m.logger.Debugf("applied migration version=%d name=%q direction=down elapsed=%s",
+					mig.Version, mig.Name, time.Since(stepStart))
 			}
 
+			m.logger.Infof("down completed old=%d new=%d elapsed=%s", oldVersion, newVersion, time.Since(overallStart))
+
 			return
 		}
 	}
@@ -191,80 +385,27 @@ func (m *Migrator) Down(db *mongo.Database) (oldVersion int64, newVersion int64,
 
 // Reset resets database to the zero-revision.
 func (m *Migrator) Reset(db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
-	opts := options.Find()
-	opts.SetSort(bson.D{{"version", 1}})
-
-	cursor, err := db.Collection("migrations").Find(context.TODO(), bson.D{}, opts)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			err = ErrorMigrationsAreNotInitialized
-		}
-		return
-	}
-	defer cursor.Close(context.TODO())
-
-	history := []migration.Migration{}
-
-	for cursor.Next(context.TODO()) {
-		var mig migration.Migration
-
-		if err = cursor.Decode(&mig); err != nil {
-			return
-		}
-
-		mig.Stored = true
-		history = append(history, mig)
-	}
-
-	length := len(history)
-	if length > 0 {
-		version := history[length-1].Version
-		oldVersion = version
-		newVersion = version
-	} else {
-		return
-	}
-
-	coll := db.Collection("migrations", migration.MajorityOpts())
-	correlated, err := m.correlateMigrations(history, m.migrations)
-	if err != nil {
-		return
-	}
-
-	for i := len(correlated) - 1; i >= 0; i-- {
-		migr := correlated[i]
-
-		if i > 0 {
-			newVersion = correlated[i-1].Version
-		} else {
-			newVersion = migr.Version
-		}
-
-		if err = migr.Down(db); err != nil {
-			return
-		}
-
-		migr.Stored = true
-
-		// don't delete zero migration
-		if migr.Version > 0 {
-			if _, err = coll.DeleteOne(context.TODO(), bson.D{{"version", migr.Version}}); err != nil {
-				return
-			}
-		}
-	}
+	return m.ResetContext(context.Background(), db)
+}
 
-	return
+// ResetContext is the context-aware variant of `Reset`.
+func (m *Migrator) ResetContext(ctx context.Context, db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
+	return m.DownToContext(ctx, db, 0)
 }
 
 // Version returns current database revision version.
 func (m *Migrator) Version(db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
+	return m.VersionContext(context.Background(), db)
+}
+
+// VersionContext is the context-aware variant of `Version`.
+func (m *Migrator) VersionContext(ctx context.Context, db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
 	opts := options.FindOne()
 	opts.SetSort(bson.D{{"version", -1}})
 
 	var mig migration.Migration
 
-	result := db.Collection("migrations").FindOne(context.TODO(), bson.D{}, opts)
+	result := db.Collection("migrations").FindOne(ctx, bson.D{}, opts)
 	if err = result.Err(); err != nil {
 		if err == mongo.ErrNoDocuments {
 			err = ErrorMigrationsAreNotInitialized
@@ -281,7 +422,19 @@ func (m *Migrator) Version(db *mongo.Database) (oldVersion int64, newVersion int
 
 // SetVersion forces database revisiton version.
 func (m *Migrator) SetVersion(db *mongo.Database, target int64) (oldVersion int64, newVersion int64, err error) {
-	oldVersion, _, err = m.Version(db)
+	return m.SetVersionContext(context.Background(), db, target)
+}
+
+// SetVersionContext is the context-aware variant of `SetVersion`.
+func (m *Migrator) SetVersionContext(ctx context.Context, db *mongo.Database, target int64) (oldVersion int64, newVersion int64, err error) {
+	if err = m.acquireLock(ctx, db); err != nil {
+		return
+	}
+	defer m.releaseLockLogged(ctx, db)
+
+	start := time.Now()
+
+	oldVersion, _, err = m.VersionContext(ctx, db)
 	if err != nil {
 		return
 	}
@@ -306,18 +459,74 @@ func (m *Migrator) SetVersion(db *mongo.Database, target int64) (oldVersion int6
 	}
 
 	coll := db.Collection("migrations", migration.MajorityOpts())
-	if err = coll.Drop(context.TODO()); err != nil {
+	if err = coll.Drop(ctx); err != nil {
+		m.logger.Errorf("set_version to %d failed: %v", target, err)
 		return
 	}
 
-	if _, err = coll.InsertMany(context.TODO(), migs); err != nil {
+	if _, err = coll.InsertMany(ctx, migs); err != nil {
+		m.logger.Errorf("set_version to %d failed: %v", target, err)
 		return
 	}
 
 	newVersion = migs[len(migs)-1].(migration.Migration).Version
+	m.logger.Infof("set version from=%d to=%d elapsed=%s", oldVersion, newVersion, time.Since(start))
 	return
 }
 
+// usesTransaction reports whether a specific migration should run inside a session transaction,
+// honoring its per-migration override if one is set.
+func (m *Migrator) usesTransaction(migr *migration.Migration) bool {
+	if migr.Transactions != nil {
+		return *migr.Transactions
+	}
+
+	return m.useTransactions
+}
+
+// withTransaction executes fn inside a MongoDB session transaction when enabled is true, so that fn's
+// writes either all commit or all roll back together. On a deployment that does not support
+// transactions (typically a standalone server) it falls back to running fn directly against ctx and
+// reports the fallback via `ErrorTransactionsUnsupported`.
+func (m *Migrator) withTransaction(ctx context.Context, enabled bool, fn func(ctx context.Context) error) (err error) {
+	if !enabled {
+		return fn(ctx)
+	}
+
+	sess, err := m.client.StartSession()
+	if err != nil {
+		if isTransactionsUnsupported(err) {
+			if err = fn(ctx); err != nil {
+				return
+			}
+
+			err = ErrorTransactionsUnsupported
+		}
+
+		return
+	}
+	defer sess.EndSession(ctx)
+
+	_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	if err != nil && isTransactionsUnsupported(err) {
+		if err = fn(ctx); err != nil {
+			return
+		}
+
+		err = ErrorTransactionsUnsupported
+	}
+
+	return
+}
+
+// isTransactionsUnsupported reports whether an error returned by the driver indicates that the
+// connected MongoDB deployment does not support multi-document transactions.
+func isTransactionsUnsupported(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "replica set")
+}
+
 func (m *Migrator) parseVersion(required bool, args ...string) (version int64, err error) {
 	if len(args) == 0 {
 		if required {
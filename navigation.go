@@ -0,0 +1,158 @@
+package migrator
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Devoter/mongo-migrator/migration"
+)
+
+// Migrations returns the full sorted list of registered migrations, including the implicit zero
+// migration, so that callers can navigate history programmatically via `migration.Migrations`'
+// `Current`/`Next`/`Previous` helpers.
+func (m *Migrator) Migrations() []migration.Migration {
+	migrations := make([]migration.Migration, len(m.migrations))
+	copy(migrations, m.migrations)
+
+	return migrations
+}
+
+// DownTo downgrades the database to exactly `target`, running `Down` on every applied migration newer
+// than `target` in reverse order. Unlike `SetVersion`, it actually executes each migration's `Down`
+// function rather than just rewriting the stored history. When `target` is not an applied version, it
+// stops at the newest applied version below `target` and returns that as `newVersion`.
+func (m *Migrator) DownTo(db *mongo.Database, target int64) (oldVersion int64, newVersion int64, err error) {
+	return m.DownToContext(context.Background(), db, target)
+}
+
+// DownToContext is the context-aware variant of `DownTo`.
+func (m *Migrator) DownToContext(ctx context.Context, db *mongo.Database, target int64) (oldVersion int64, newVersion int64, err error) {
+	if err = m.acquireLock(ctx, db); err != nil {
+		return
+	}
+	defer m.releaseLockLogged(ctx, db)
+
+	history, err := m.fetchHistory(ctx, db)
+	if err != nil {
+		return
+	}
+
+	length := len(history)
+	if length > 0 {
+		version := history[length-1].Version
+		oldVersion = version
+		newVersion = version
+	} else {
+		return
+	}
+
+	coll := db.Collection("migrations", migration.MajorityOpts())
+	correlated, err := m.correlateMigrations(history, m.migrations)
+	if err != nil {
+		return
+	}
+
+	overallStart := time.Now()
+	applied := 0
+
+	for i := len(correlated) - 1; i >= 0 && correlated[i].Version > target; i-- {
+		migr := correlated[i]
+		newVersion = target
+
+		if i > 0 {
+			newVersion = correlated[i-1].Version
+		}
+
+		stepStart := time.Now()
+		fellBackFromTransaction := false
+
+		err = m.withTransaction(ctx, m.usesTransaction(&migr), func(txCtx context.Context) error {
+			if e := migr.Down(txCtx, db); e != nil {
+				return e
+			}
+
+			_, e := coll.DeleteOne(txCtx, bson.D{{"version", migr.Version}})
+			return e
+		})
+		if err == ErrorTransactionsUnsupported {
+			fellBackFromTransaction = true
+			err = nil
+		} else if err != nil {
+			m.logger.Errorf("migration version=%d name=%q direction=down failed: %v", migr.Version, migr.Name, err)
+			return
+		}
+
+		if fellBackFromTransaction {
+			m.logger.Debugf("migration version=%d ran without transaction support", migr.Version)
+		}
+
+		applied++
+		m.logger.Debugf("applied migration version=%d name=%q direction=down elapsed=%s",
+			migr.Version, migr.Name, time.Since(stepStart))
+	}
+
+	m.logger.Infof("down_to completed old=%d new=%d applied=%d elapsed=%s", oldVersion, newVersion, applied, time.Since(overallStart))
+
+	return
+}
+
+// UpByOne upgrades the database by exactly one pending migration. If there is no pending migration it
+// is a no-op, returning the current version as both `oldVersion` and `newVersion`.
+func (m *Migrator) UpByOne(db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
+	return m.UpByOneContext(context.Background(), db)
+}
+
+// UpByOneContext is the context-aware variant of `UpByOne`.
+func (m *Migrator) UpByOneContext(ctx context.Context, db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
+	if err = m.acquireLock(ctx, db); err != nil {
+		return
+	}
+	defer m.releaseLockLogged(ctx, db)
+
+	return m.upByOne(ctx, db)
+}
+
+// upByOne runs the `UpByOne` logic without acquiring the advisory lock itself. Callers must already
+// hold it; use this instead of `UpByOneContext` when composing multiple locked steps under a single
+// lock acquisition, e.g. `RedoContext`.
+func (m *Migrator) upByOne(ctx context.Context, db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
+	oldVersion, _, err = m.VersionContext(ctx, db)
+	if err != nil {
+		return
+	}
+
+	next, nextErr := migration.Migrations(m.migrations).Next(oldVersion)
+	if nextErr != nil {
+		newVersion = oldVersion
+		return
+	}
+
+	return m.up(ctx, db, next.Version)
+}
+
+// Redo reverts the most recently applied migration and then reapplies it.
+func (m *Migrator) Redo(db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
+	return m.RedoContext(context.Background(), db)
+}
+
+// RedoContext is the context-aware variant of `Redo`. It acquires the advisory lock once for the whole
+// revert-then-reapply sequence, rather than once per step, so a concurrent `Up`/`Down`/`Reset` can't
+// interleave between the two halves of the redo.
+func (m *Migrator) RedoContext(ctx context.Context, db *mongo.Database) (oldVersion int64, newVersion int64, err error) {
+	if err = m.acquireLock(ctx, db); err != nil {
+		return
+	}
+	defer m.releaseLockLogged(ctx, db)
+
+	oldVersion, newVersion, err = m.down(ctx, db)
+	if err != nil || newVersion == oldVersion {
+		// nothing was reverted (e.g. only the zero migration is applied), so there is nothing to reapply
+		return
+	}
+
+	_, newVersion, err = m.upByOne(ctx, db)
+	return
+}
@@ -0,0 +1,156 @@
+package migrator
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Devoter/mongo-migrator/migration"
+)
+
+// MigrationStatus describes a single known migration's state relative to the database: whether it has
+// been applied, when, and whether it is absent (stored in the database but missing from the registered
+// migrations).
+type MigrationStatus struct {
+	Version   int64     `json:"version"`
+	Name      string    `json:"name"`
+	Applied   bool      `json:"applied"`
+	AppliedAt time.Time `json:"appliedAt,omitempty"`
+	Absent    bool      `json:"absent"`
+}
+
+// Status returns the state of every known migration, flagging migrations that are stored in the
+// database but absent from the registered migrations.
+func (m *Migrator) Status(db *mongo.Database) ([]MigrationStatus, error) {
+	return m.StatusContext(context.Background(), db)
+}
+
+// StatusContext is the context-aware variant of `Status`.
+func (m *Migrator) StatusContext(ctx context.Context, db *mongo.Database) ([]MigrationStatus, error) {
+	history, err := m.fetchHistory(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]migration.Migration, len(history))
+	for _, mig := range history {
+		applied[mig.Version] = mig
+	}
+
+	known := make(map[int64]bool, len(m.migrations))
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+
+	for _, migr := range m.migrations {
+		known[migr.Version] = true
+		status := MigrationStatus{Version: migr.Version, Name: migr.Name}
+
+		if mig, ok := applied[migr.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = mig.AppliedAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	for _, mig := range history {
+		if !known[mig.Version] {
+			statuses = append(statuses, MigrationStatus{
+				Version:   mig.Version,
+				Name:      mig.Name,
+				Applied:   true,
+				AppliedAt: mig.AppliedAt,
+				Absent:    true,
+			})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Plan returns the ordered list of migrations that `Up`/`Down`/`Reset` would run in order to reach
+// target (or the latest registered version when target is `-1`), without executing anything. When
+// target is below the current applied version, the returned migrations are in `Down` order, i.e. the
+// same migrations and order `DownTo` would use to roll back to target.
+func (m *Migrator) Plan(db *mongo.Database, target int64) ([]migration.Migration, error) {
+	return m.PlanContext(context.Background(), db, target)
+}
+
+// PlanContext is the context-aware variant of `Plan`.
+func (m *Migrator) PlanContext(ctx context.Context, db *mongo.Database, target int64) ([]migration.Migration, error) {
+	history, err := m.fetchHistory(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	length := len(history)
+	if length > 0 && target != -1 && target < history[length-1].Version {
+		return m.planDown(history, target)
+	}
+
+	merged := m.mergeMigrations(history, m.migrations, target)
+	plan := make([]migration.Migration, 0, len(merged))
+
+	for _, migr := range merged {
+		if !migr.Stored {
+			plan = append(plan, migr)
+		}
+	}
+
+	return plan, nil
+}
+
+// planDown returns the migrations that `DownTo(db, target)` would roll back, in the same reverse order
+// it would apply them in.
+func (m *Migrator) planDown(history []migration.Migration, target int64) ([]migration.Migration, error) {
+	correlated, err := m.correlateMigrations(history, m.migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := make([]migration.Migration, 0, len(correlated))
+
+	for i := len(correlated) - 1; i >= 0 && correlated[i].Version > target; i-- {
+		plan = append(plan, correlated[i])
+	}
+
+	return plan, nil
+}
+
+// fetchHistory returns the sorted list of migrations already stored in the `migrations` collection.
+func (m *Migrator) fetchHistory(ctx context.Context, db *mongo.Database) ([]migration.Migration, error) {
+	opts := options.Find()
+	opts.SetSort(bson.D{{"version", 1}})
+
+	cursor, err := db.Collection("migrations").Find(ctx, bson.D{}, opts)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			err = ErrorMigrationsAreNotInitialized
+		}
+
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	history := []migration.Migration{}
+
+	for cursor.Next(ctx) {
+		var mig migration.Migration
+
+		if err := cursor.Decode(&mig); err != nil {
+			return nil, err
+		}
+
+		mig.Stored = true
+		history = append(history, mig)
+	}
+
+	return history, nil
+}